@@ -3,15 +3,29 @@
 package main
 
 import (
-	"crypto/sha256"
+	"bytes"
+	"context"
+	"crypto"
+	_ "crypto/md5"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	_ "golang.org/x/crypto/blake2b"
+	_ "golang.org/x/crypto/blake2s"
 )
 
 // check is the smallest unit that can show up on a scoring report. It holds all
@@ -40,6 +54,42 @@ type cond struct {
 	Key   string
 	Value string
 	After string
+
+	// Timeout, parsed with time.ParseDuration, bounds how long
+	// CommandContains and CommandOutput let the command run before it's
+	// killed. Defaults to conf.CommandTimeout when empty.
+	Timeout string
+	// Dir sets the working directory for CommandContains and CommandOutput.
+	Dir string
+	// Env appends "KEY=VALUE" entries to the environment CommandContains and
+	// CommandOutput run the command with.
+	Env []string
+
+	// Algorithm selects the digest used by FileEquals and FileHashIn. One of
+	// sha1, sha256, sha512, md5, blake2b, or blake2s; defaults to sha256.
+	Algorithm string
+
+	// MaxDepth bounds how many directory levels DirContains descends into
+	// below Path: 0 inspects only files directly inside Path, a negative
+	// value removes the bound entirely, and any other value limits descent
+	// to that many subdirectory levels. Left nil -- as every check written
+	// before this field existed leaves it -- DirContains walks the whole
+	// tree with no bound, matching its original behavior; use a pointer to
+	// distinguish that from an explicit 0, since the zero value of a plain
+	// int would otherwise silently mean "top level only".
+	MaxDepth *int
+	// NameRegex, if set, restricts DirContains to files whose base name
+	// matches the given pattern.
+	NameRegex string
+	// MatchAll requires every candidate file considered by DirContains to
+	// contain Value, rather than just one.
+	MatchAll bool
+
+	// Count, if set, asserts on the number of matches FileContains finds
+	// instead of just whether one exists, e.g. ">=3", "==0", "<5". A bare
+	// number is shorthand for "==N".
+	Count string
+
 	regex bool
 }
 
@@ -65,10 +115,24 @@ func (c cond) requireArgs(args ...interface{}) {
 			continue
 		}
 
+		// Non-string fields (MaxDepth, MatchAll, ...) have their own
+		// meaningful zero values and aren't covered by this check.
+		if v.Field(i).Kind() != reflect.String {
+			continue
+		}
+
+		// An arg of "Field?" marks that field as recognized but optional:
+		// it won't be flagged as unused, but it also isn't required.
 		required := false
+		recognized := false
 		for _, a := range args {
-			if vType.Field(i).Name == a {
-				required = true
+			arg := a.(string)
+			optional := strings.HasSuffix(arg, "?")
+			if vType.Field(i).Name == strings.TrimSuffix(arg, "?") {
+				recognized = true
+				if !optional {
+					required = true
+				}
 				break
 			}
 		}
@@ -77,7 +141,7 @@ func (c cond) requireArgs(args ...interface{}) {
 			if v.Field(i).String() == "" {
 				fail(c.Type+":", "missing required argument '"+vType.Field(i).Name+"'")
 			}
-		} else if v.Field(i).String() != "" {
+		} else if v.Field(i).String() != "" && !recognized {
 			warn(c.Type+":", "specifying unused argument '"+vType.Field(i).Name+"'")
 		}
 	}
@@ -89,18 +153,106 @@ func (c cond) String() string {
 	typeOfS := v.Type()
 
 	for i := 0; i < v.NumField(); i++ {
-		if v.Field(i).String() == "" {
+		f := v.Field(i)
+		if f.Kind() == reflect.String && f.String() == "" {
+			continue
+		}
+		if (f.Kind() == reflect.Bool && !f.Bool()) || (f.Kind() == reflect.Int && f.Int() == 0) {
 			continue
 		}
-		output += fmt.Sprintf("\t%s: %v\n", typeOfS.Field(i).Name, v.Field(i).String())
+		if f.Kind() == reflect.Ptr {
+			if f.IsNil() {
+				continue
+			}
+			output += fmt.Sprintf("\t%s: %v\n", typeOfS.Field(i).Name, f.Elem().Interface())
+			continue
+		}
+		output += fmt.Sprintf("\t%s: %v\n", typeOfS.Field(i).Name, f.Interface())
 	}
 	return output
 }
 
-func handleReflectPanic(condFunc string) {
-	if r := recover(); r != nil {
-		fail("Check type does not exist: "+condFunc, "("+r.(*reflect.ValueError).Error()+")")
+// CheckType is implemented by every check type that can be referenced by a
+// condition's Type field (optionally suffixed with "Not" and/or "Regex").
+// Registering a CheckType, rather than editing the cond struct and
+// switching on Type by hand, is what lets platform-specific packages
+// (linux, windows, ...) and tests add or fake check types without touching
+// this file.
+type CheckType interface {
+	// Name is the bare check type, e.g. "FileContains" -- without the
+	// "Not"/"Regex" suffixes, which runCheck strips before looking it up.
+	Name() string
+	// Run executes the check against the given condition.
+	Run(cond) (bool, error)
+	// RequiredArgs lists the cond fields this check type needs. runCheck
+	// doesn't consult it; Run is responsible for enforcing it (typically
+	// via cond.requireArgs).
+	RequiredArgs() []string
+}
+
+var checkRegistry = map[string]CheckType{}
+
+// RegisterCheck makes a check type available to runCheck under its Name.
+// Call it from an init function. Registering the same name twice is a
+// programming error.
+func RegisterCheck(ct CheckType) {
+	if _, exists := checkRegistry[ct.Name()]; exists {
+		fail("check type already registered:", ct.Name())
+		return
+	}
+	checkRegistry[ct.Name()] = ct
+}
+
+// stripCheckSuffixes splits a condition's Type into the base check name and
+// whether it carries the "Not" and/or "Regex" suffixes. A type combining
+// both, e.g. "FileContainsNotRegex", always puts "Not" before "Regex", so
+// "Regex" has to be stripped first -- stripping "Not" first checks the
+// suffix against "...NotRegex", which never matches "Not" and silently
+// drops the negation.
+func stripCheckSuffixes(t string) (name string, negated, isRegex bool) {
+	name = t
+	if strings.HasSuffix(name, "Regex") {
+		isRegex = true
+		name = name[:len(name)-len("Regex")]
+	}
+	if strings.HasSuffix(name, "Not") {
+		negated = true
+		name = name[:len(name)-len("Not")]
 	}
+	return name, negated, isRegex
+}
+
+// runRegisteredCheck runs condFunc via the CheckType registry. The third
+// return value reports whether condFunc was registered at all.
+func runRegisteredCheck(cond cond, condFunc string) (result bool, err error, ok bool) {
+	ct, found := checkRegistry[condFunc]
+	if !found {
+		return false, nil, false
+	}
+	result, err = ct.Run(cond)
+	return result, err, true
+}
+
+// runReflectedCheck dispatches condFunc by calling the matching cond method
+// directly, the way runCheck always did before the CheckType registry
+// existed. It recovers from the panic reflect raises when condFunc isn't a
+// method on cond, reporting that as ok == false rather than crashing a
+// scoring run.
+//
+// runCheck uses it for any condFunc that has no registered CheckType, so a
+// cond method without a RegisterCheck call still runs through this path.
+func runReflectedCheck(cond cond, condFunc string) (result bool, err error, ok bool) {
+	defer func() {
+		recover()
+	}()
+
+	vals := reflect.ValueOf(cond).MethodByName(condFunc).Call([]reflect.Value{})
+	result = vals[0].Bool()
+	if errVal := vals[1]; !errVal.IsNil() {
+		err = errVal.Interface().(error)
+	}
+	ok = true
+	return result, err, ok
 }
 
 // runCheck executes a single condition check.
@@ -111,54 +263,46 @@ func runCheck(cond cond) bool {
 	defer obfuscateCond(&cond)
 	debug("Running condition:\n", cond)
 
-	not := "Not"
-	regex := "Regex"
-	condFunc := ""
-	negation := false
-	cond.regex = false
-
 	// Ensure that condition type is a valid length
-	if len(cond.Type) <= len(regex) {
+	if len(cond.Type) <= len("Regex") {
 		fail(`Condition type "` + cond.Type + `" is not long enough to be valid. Do you have a "type = 'CheckTypeHere'" for all check conditions?`)
 		return false
 	}
-	condFunc = cond.Type
-	if cond.Type[len(cond.Type)-len(not):len(cond.Type)] == not {
-		negation = true
-		condFunc = cond.Type[:len(cond.Type)-len(not)]
-	}
-	if cond.Type[len(cond.Type)-len(regex):len(cond.Type)] == regex {
-		cond.regex = true
-		condFunc = cond.Type[:len(cond.Type)-len(regex)]
-	}
 
-	// Catch panic if check type doesn't exist
-	defer handleReflectPanic(condFunc)
+	condFunc, negation, isRegex := stripCheckSuffixes(cond.Type)
+	cond.regex = isRegex
 
-	// Using reflection to find the correct function to call.
-	vals := reflect.ValueOf(cond).MethodByName(condFunc).Call([]reflect.Value{})
-	result := vals[0].Bool()
-	err := vals[1]
+	result, err, ok := runRegisteredCheck(cond, condFunc)
+	if !ok {
+		// Fallback for check types that are cond methods but aren't in the
+		// registry.
+		result, err, ok = runReflectedCheck(cond, condFunc)
+	}
+	if !ok {
+		fail("Check type does not exist:", condFunc)
+		return false
+	}
 
 	if negation {
 		debug("Result is", !result, "(was", result, "before negation) and error is", err)
-		return err.IsNil() && !result
+		return err == nil && !result
 	}
 
 	debug("Result is", result, "and error is", err)
 
-	if verboseEnabled && !err.IsNil() {
+	if verboseEnabled && err != nil {
 		warn(condFunc, "returned an error:", err)
 	}
 
-	return err.IsNil() && result
+	return err == nil && result
 }
 
 // CommandContains checks if a given shell command contains a certain string.
-// This check will always fail if the command returns an error.
+// This check will always fail if the command returns an error, including
+// when it's killed for exceeding Timeout.
 func (c cond) CommandContains() (bool, error) {
-	c.requireArgs("Cmd", "Value")
-	out, err := shellCommandOutput(c.Cmd)
+	c.requireArgs("Cmd", "Value", "Timeout?", "Dir?", "User?")
+	out, err := shellCommandOutput(c)
 	if err != nil {
 		return false, err
 	}
@@ -170,16 +314,106 @@ func (c cond) CommandContains() (bool, error) {
 }
 
 // CommandOutput checks if a given shell command produces an exact output.
-// This check will always fail if the command returns an error.
+// This check will always fail if the command returns an error, including
+// when it's killed for exceeding Timeout.
 func (c cond) CommandOutput() (bool, error) {
-	c.requireArgs("Cmd", "Value")
-	out, err := shellCommandOutput(c.Cmd)
+	c.requireArgs("Cmd", "Value", "Timeout?", "Dir?", "User?")
+	out, err := shellCommandOutput(c)
 	return strings.TrimSpace(out) == c.Value, err
 }
 
-// DirContains returns true if any file in the directory contains the string value provided.
+// shellCommandOutput runs c.Cmd in a shell and returns its combined
+// stdout/stderr. Timeout (falling back to conf.CommandTimeout when unset)
+// bounds how long the command may run before it's killed; Dir and Env scope
+// its working directory and environment, and User, if set, runs it as that
+// user. A command that hits its deadline is reported as an explicit timeout
+// error rather than whatever exec.CommandContext happens to return.
+//
+// The shell itself is put in its own process group via
+// prepareCommandGroup, and killCommandGroup is used to kill that whole
+// group on timeout, not just the shell process exec.CommandContext would
+// otherwise kill: /bin/sh is commonly dash, which forks rather than execs
+// for ordinary commands, so killing only the shell leaves a runaway
+// grandchild (and the scoring run blocked on its stdout/stderr pipe)
+// behind.
+func shellCommandOutput(c cond) (string, error) {
+	timeout := conf.CommandTimeout
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return "", fmt.Errorf("%s: invalid timeout %q: %w", c.Type, c.Timeout, err)
+		}
+		timeout = d
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", c.Cmd)
+	} else {
+		cmd = exec.Command("sh", "-c", c.Cmd)
+	}
+	if c.Dir != "" {
+		cmd.Dir = c.Dir
+	}
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	prepareCommandGroup(cmd)
+	if c.User != "" {
+		if err := setCommandUser(cmd, c.User); err != nil {
+			return "", fmt.Errorf("%s: %w", c.Type, err)
+		}
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- cmd.Wait() }()
+
+	select {
+	case <-ctx.Done():
+		if err := killCommandGroup(cmd); err != nil {
+			warn(c.Type+":", "failed to kill timed out command:", err)
+		}
+		<-waitErr
+		warn(c.Type+":", "command timed out after", timeout)
+		return out.String(), fmt.Errorf("%s: command timed out after %s", c.Type, timeout)
+	case err := <-waitErr:
+		return out.String(), err
+	}
+}
+
+// dirDepth returns how many directory levels below base the given path sits.
+// A path equal to base is depth 0; a path one subdirectory down is depth 1,
+// and so on.
+func dirDepth(base, path string) int {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// DirContains returns true if a file in the directory contains the string
+// value provided. By default any one matching file is enough, but MatchAll
+// requires every candidate file to contain the value. NameRegex, if set,
+// restricts which files are inspected, and MaxDepth bounds how many
+// directory levels are descended into (0 = only Path itself, a negative
+// value or leaving it nil = unlimited).
 func (c cond) DirContains() (bool, error) {
-	c.requireArgs("Path", "Value")
+	c.requireArgs("Path", "Value", "NameRegex?")
 	result, err := cond{
 		Path: c.Path,
 	}.PathExists()
@@ -190,11 +424,37 @@ func (c cond) DirContains() (bool, error) {
 		return false, errors.New("path does not exist")
 	}
 
+	var nameRe *regexp.Regexp
+	if c.NameRegex != "" {
+		nameRe, err = regexp.Compile(c.NameRegex)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	maxDepth := -1
+	if c.MaxDepth != nil {
+		maxDepth = *c.MaxDepth
+	}
+
 	var files []string
 	err = filepath.Walk(c.Path, func(path string, info os.FileInfo, err error) error {
-		if !info.IsDir() {
-			files = append(files, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != c.Path && maxDepth >= 0 && dirDepth(c.Path, path) > maxDepth {
+				return filepath.SkipDir
+			}
+			return nil
 		}
+		if maxDepth >= 0 && dirDepth(c.Path, filepath.Dir(path)) > maxDepth {
+			return nil
+		}
+		if nameRe != nil && !nameRe.MatchString(info.Name()) {
+			return nil
+		}
+		files = append(files, path)
 		if len(files) > 10000 {
 			return errors.New("attempted to index too many files in recursive search")
 		}
@@ -205,61 +465,230 @@ func (c cond) DirContains() (bool, error) {
 		return false, err
 	}
 
+	if len(files) == 0 {
+		return false, nil
+	}
+
+	// The per-file FileContains calls are internal: clearing Type keeps their
+	// requireArgs from flagging DirContains-only fields such as NameRegex.
+	c.Type = ""
 	for _, file := range files {
 		c.Path = file
 		result, err := c.FileContains()
 		if os.IsPermission(err) {
 			return false, err
 		}
-		if result {
-			return result, nil
+		if err != nil {
+			if c.MatchAll {
+				return false, err
+			}
+			continue
+		}
+		if c.MatchAll {
+			if !result {
+				return false, nil
+			}
+		} else if result {
+			return true, nil
 		}
 	}
-	return false, nil
+	return c.MatchAll, nil
+}
+
+var (
+	regexCacheMu sync.Mutex
+	regexCache   = map[string]*regexp.Regexp{}
+)
+
+// compiledRegex compiles pattern once and caches the result, since scoring
+// configs tend to reuse the same expressions across many checks.
+func compiledRegex(pattern string) (*regexp.Regexp, error) {
+	regexCacheMu.Lock()
+	defer regexCacheMu.Unlock()
+	if re, ok := regexCache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache[pattern] = re
+	return re, nil
+}
+
+// countOperators lists the comparisons evalCount understands, longest
+// first so ">=" and "<=" aren't mistaken for a bare ">" or "<".
+var countOperators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// evalCount parses a Count expression like ">=3", "==0", or "5" (shorthand
+// for "==5") and reports whether got satisfies it.
+func evalCount(expr string, got int) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	op := "=="
+	numPart := expr
+	for _, o := range countOperators {
+		if strings.HasPrefix(expr, o) {
+			op = o
+			numPart = expr[len(o):]
+			break
+		}
+	}
+
+	want, err := strconv.Atoi(strings.TrimSpace(numPart))
+	if err != nil {
+		return false, fmt.Errorf("invalid count expression %q: %w", expr, err)
+	}
+
+	switch op {
+	case ">=":
+		return got >= want, nil
+	case "<=":
+		return got <= want, nil
+	case "!=":
+		return got != want, nil
+	case ">":
+		return got > want, nil
+	case "<":
+		return got < want, nil
+	default:
+		return got == want, nil
+	}
 }
 
-// FileContains determines whether a file contains a given regular expression.
+// FileContains determines whether a file contains a given regular
+// expression, or with Count set, whether it appears a particular number of
+// times (e.g. ">=3", "==0", "<5"). The pattern is compiled once -- honoring
+// inline flags like (?m) and (?s) -- and matched against the file's full
+// contents rather than line by line, so anchors and multi-line patterns
+// work as expected.
 //
-// Newlines in regex may not work as expected, especially on Windows. It's
-// best to not use these (ex. ^ and $).
+// A malformed pattern is only caught here, the first time the check
+// actually runs against a scoring target -- this tree has no config-load
+// step for FileContains conditions to hook a precompile-and-validate pass
+// into, so catching it earlier, during config load, isn't done.
 func (c cond) FileContains() (bool, error) {
-	c.requireArgs("Path", "Value")
+	c.requireArgs("Path", "Value", "Count?")
 	fileContent, err := readFile(c.Path)
 	if err != nil {
 		return false, err
 	}
-	found := false
-	for _, line := range strings.Split(fileContent, "\n") {
-		if c.regex {
-			found, err = regexp.Match(c.Value, []byte(line))
-			if err != nil {
-				return false, err
-			}
-		} else {
-			found = strings.Contains(line, c.Value)
-		}
-		if found {
-			break
+
+	var count int
+	if c.regex {
+		re, err := compiledRegex(c.Value)
+		if err != nil {
+			return false, err
 		}
+		count = len(re.FindAllStringIndex(fileContent, -1))
+	} else {
+		count = strings.Count(fileContent, c.Value)
+	}
+
+	if c.Count != "" {
+		return evalCount(c.Count, count)
 	}
-	return found, err
+	return count > 0, nil
 }
 
-// FileEquals calculates the SHA256 sum of a file and compares it with the hash
-// provided in the check.
+// hashAlgorithms maps the Algorithm values accepted on cond to their
+// crypto.Hash registration. blake2b and blake2s are registered by their
+// golang.org/x/crypto side-effect imports above.
+var hashAlgorithms = map[string]crypto.Hash{
+	"md5":     crypto.MD5,
+	"sha1":    crypto.SHA1,
+	"sha256":  crypto.SHA256,
+	"sha512":  crypto.SHA512,
+	"blake2b": crypto.BLAKE2b_256,
+	"blake2s": crypto.BLAKE2s_256,
+}
+
+// fileHash hashes the file at path with the named algorithm, defaulting to
+// sha256 when algorithm is empty.
+func fileHash(path, algorithm string) (string, error) {
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	h, ok := hashAlgorithms[strings.ToLower(algorithm)]
+	if !ok {
+		return "", fmt.Errorf("unsupported hash algorithm %q", algorithm)
+	}
+	if !h.Available() {
+		return "", fmt.Errorf("hash algorithm %q is not available", algorithm)
+	}
+
+	fileContent, err := readFile(path)
+	if err != nil {
+		return "", err
+	}
+	hasher := h.New()
+	if _, err := hasher.Write([]byte(fileContent)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// FileEquals calculates the checksum of a file and compares it with the hash
+// provided in the check. Algorithm selects the digest (sha1, sha256, sha512,
+// md5, blake2b, or blake2s) and defaults to sha256. The comparison is
+// case-insensitive, like FileHashIn's, so a hash pasted from a tool like
+// sha256sum (lowercase) or certutil (uppercase) works either way.
 func (c cond) FileEquals() (bool, error) {
-	c.requireArgs("Path", "Value")
-	fileContent, err := readFile(c.Path)
+	c.requireArgs("Path", "Value", "Algorithm?")
+	hash, err := fileHash(c.Path, c.Algorithm)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(hash, c.Value), nil
+}
+
+// acceptableHashes parses the set of hashes FileHashIn will accept. Value is
+// either a newline- or comma-separated list of hashes, or the path to an
+// existing hash-list file in the standard `hash  filename` format (such as
+// the output of sha256sum); only the first field of each line is read.
+func (c cond) acceptableHashes() ([]string, error) {
+	list := c.Value
+	if exists, err := (cond{Path: c.Value}).PathExists(); err == nil && exists {
+		contents, err := readFile(c.Value)
+		if err != nil {
+			return nil, err
+		}
+		list = contents
+	}
+
+	var hashes []string
+	for _, line := range strings.FieldsFunc(list, func(r rune) bool {
+		return r == '\n' || r == ','
+	}) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		hashes = append(hashes, fields[0])
+	}
+	return hashes, nil
+}
+
+// FileHashIn checks whether a file's checksum (computed the same way as
+// FileEquals, honoring Algorithm) is a member of an acceptable set of
+// hashes, letting content authors validate against several known-good
+// versions in a single condition. See acceptableHashes for how Value is
+// interpreted.
+func (c cond) FileHashIn() (bool, error) {
+	c.requireArgs("Path", "Value", "Algorithm?")
+	hash, err := fileHash(c.Path, c.Algorithm)
 	if err != nil {
 		return false, err
 	}
-	hasher := sha256.New()
-	_, err = hasher.Write([]byte(fileContent))
+	hashes, err := c.acceptableHashes()
 	if err != nil {
 		return false, err
 	}
-	hash := hex.EncodeToString(hasher.Sum(nil))
-	return hash == c.Value, nil
+	for _, h := range hashes {
+		if strings.EqualFold(h, hash) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // PathExists is a wrapper around os.Stat and os.IsNotExist, and determines
@@ -274,3 +703,58 @@ func (c cond) PathExists() (bool, error) {
 	}
 	return true, nil
 }
+
+// The remaining types are the CheckType registrations for the built-ins
+// above, each a thin adapter onto the cond method that does the real work.
+
+type commandContainsCheck struct{}
+
+func (commandContainsCheck) Name() string             { return "CommandContains" }
+func (commandContainsCheck) RequiredArgs() []string   { return []string{"Cmd", "Value"} }
+func (commandContainsCheck) Run(c cond) (bool, error) { return c.CommandContains() }
+
+type commandOutputCheck struct{}
+
+func (commandOutputCheck) Name() string             { return "CommandOutput" }
+func (commandOutputCheck) RequiredArgs() []string   { return []string{"Cmd", "Value"} }
+func (commandOutputCheck) Run(c cond) (bool, error) { return c.CommandOutput() }
+
+type dirContainsCheck struct{}
+
+func (dirContainsCheck) Name() string             { return "DirContains" }
+func (dirContainsCheck) RequiredArgs() []string   { return []string{"Path", "Value"} }
+func (dirContainsCheck) Run(c cond) (bool, error) { return c.DirContains() }
+
+type fileContainsCheck struct{}
+
+func (fileContainsCheck) Name() string             { return "FileContains" }
+func (fileContainsCheck) RequiredArgs() []string   { return []string{"Path", "Value"} }
+func (fileContainsCheck) Run(c cond) (bool, error) { return c.FileContains() }
+
+type fileEqualsCheck struct{}
+
+func (fileEqualsCheck) Name() string             { return "FileEquals" }
+func (fileEqualsCheck) RequiredArgs() []string   { return []string{"Path", "Value"} }
+func (fileEqualsCheck) Run(c cond) (bool, error) { return c.FileEquals() }
+
+type fileHashInCheck struct{}
+
+func (fileHashInCheck) Name() string             { return "FileHashIn" }
+func (fileHashInCheck) RequiredArgs() []string   { return []string{"Path", "Value"} }
+func (fileHashInCheck) Run(c cond) (bool, error) { return c.FileHashIn() }
+
+type pathExistsCheck struct{}
+
+func (pathExistsCheck) Name() string             { return "PathExists" }
+func (pathExistsCheck) RequiredArgs() []string   { return []string{"Path"} }
+func (pathExistsCheck) Run(c cond) (bool, error) { return c.PathExists() }
+
+func init() {
+	RegisterCheck(commandContainsCheck{})
+	RegisterCheck(commandOutputCheck{})
+	RegisterCheck(dirContainsCheck{})
+	RegisterCheck(fileContainsCheck{})
+	RegisterCheck(fileEqualsCheck{})
+	RegisterCheck(fileHashInCheck{})
+	RegisterCheck(pathExistsCheck{})
+}
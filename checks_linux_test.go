@@ -0,0 +1,24 @@
+//go:build linux
+
+package main
+
+import (
+	"os/user"
+	"testing"
+)
+
+func TestCommandContainsUser(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Skip("cannot determine current user:", err)
+	}
+
+	c := cond{Cmd: "id -un", Value: u.Username, User: u.Username}
+	got, err := c.CommandContains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("CommandContains() with User set to the current user should still succeed")
+	}
+}
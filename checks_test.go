@@ -0,0 +1,526 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDirDepth(t *testing.T) {
+	cases := []struct {
+		base, path string
+		want       int
+	}{
+		{"/a", "/a", 0},
+		{"/a", "/a/b", 1},
+		{"/a", "/a/b/c", 2},
+		{"/a", "/a/b/c/d", 3},
+	}
+	for _, c := range cases {
+		if got := dirDepth(c.base, c.path); got != c.want {
+			t.Errorf("dirDepth(%q, %q) = %d, want %d", c.base, c.path, got, c.want)
+		}
+	}
+}
+
+// writeTree lays out dir/target.conf and dir/other.txt (depth 0),
+// dir/sub/target.conf (depth 1), and dir/sub/sub2/target.conf (depth 2),
+// each containing "needle", for exercising
+// DirContains's MaxDepth, NameRegex, and MatchAll branches.
+func writeTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "target.conf"), []byte("needle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(dir, "sub")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "target.conf"), []byte("needle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub2 := filepath.Join(sub, "sub2")
+	if err := os.MkdirAll(sub2, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub2, "target.conf"), []byte("needle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte("needle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// intPtr is a convenience for populating cond.MaxDepth, which is a *int so
+// an explicit 0 can be told apart from an omitted field.
+func intPtr(n int) *int { return &n }
+
+func TestDirContainsMaxDepth(t *testing.T) {
+	dir := writeTree(t)
+
+	cases := []struct {
+		name     string
+		maxDepth *int
+		want     bool
+	}{
+		{"depth 0 sees only the top-level file", intPtr(0), true},
+		{"depth 1 still finds a match at the top level", intPtr(1), true},
+		{"explicit negative depth is unlimited", intPtr(-1), true},
+		{"nil (omitted) depth is unlimited, same as an explicit negative value", nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cnd := cond{Path: dir, Value: "needle", MaxDepth: c.maxDepth}
+			got, err := cnd.DirContains()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("DirContains() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDirContainsMaxDepthExcludesDeeperOnly(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Only the nested file contains the needle; depth 0 must not see it.
+	if err := os.WriteFile(filepath.Join(sub, "target.conf"), []byte("needle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cnd := cond{Path: dir, Value: "needle", MaxDepth: intPtr(0)}
+	got, err := cnd.DirContains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got {
+		t.Error("DirContains() with MaxDepth 0 should not see a file one level deeper")
+	}
+}
+
+// TestDirContainsNilMaxDepthWalksWholeTree guards against a regression
+// where MaxDepth's zero value (0, meaning "top level only") was also what
+// every check written before MaxDepth existed leaves the field at -- which
+// would silently stop those checks from matching anything below Path.
+func TestDirContainsNilMaxDepthWalksWholeTree(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub", "sub2")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "target.conf"), []byte("needle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cnd := cond{Path: dir, Value: "needle"}
+	got, err := cnd.DirContains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("DirContains() with MaxDepth left unset should still walk the whole tree")
+	}
+}
+
+func TestDirContainsNameRegex(t *testing.T) {
+	dir := writeTree(t)
+
+	cnd := cond{Path: dir, Value: "needle", MaxDepth: intPtr(0), NameRegex: `\.conf$`}
+	got, err := cnd.DirContains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("DirContains() should match target.conf via NameRegex")
+	}
+
+	cnd = cond{Path: dir, Value: "needle", MaxDepth: intPtr(0), NameRegex: `\.ini$`}
+	got, err = cnd.DirContains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got {
+		t.Error("DirContains() should find nothing when NameRegex excludes every file")
+	}
+}
+
+func TestDirContainsMatchAll(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.conf"), []byte("needle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.conf"), []byte("no match here"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cnd := cond{Path: dir, Value: "needle", MatchAll: false}
+	got, err := cnd.DirContains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("DirContains() without MatchAll should pass when any file matches")
+	}
+
+	cnd = cond{Path: dir, Value: "needle", MatchAll: true}
+	got, err = cnd.DirContains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got {
+		t.Error("DirContains() with MatchAll should fail unless every file matches")
+	}
+}
+
+func TestFileHash(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		algorithm string
+		want      string
+	}{
+		{"", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"md5", "5d41402abc4b2a76b9719d911017c592"},
+		{"sha1", "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"},
+	}
+	for _, c := range cases {
+		got, err := fileHash(path, c.algorithm)
+		if err != nil {
+			t.Fatalf("fileHash(%q) = %v", c.algorithm, err)
+		}
+		if got != c.want {
+			t.Errorf("fileHash(%q) = %q, want %q", c.algorithm, got, c.want)
+		}
+	}
+}
+
+func TestFileHashUnsupportedAlgorithm(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fileHash(path, "rot13"); err == nil {
+		t.Error("fileHash() with an unsupported algorithm should return an error")
+	}
+}
+
+func TestFileEqualsIsCaseInsensitive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := cond{Path: path, Value: strings.ToUpper("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")}
+	got, err := c.FileEquals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("FileEquals() should match an uppercase hash the same way FileHashIn does")
+	}
+}
+
+func TestAcceptableHashesInlineList(t *testing.T) {
+	c := cond{Value: "abc123, def456\nghi789"}
+	got, err := c.acceptableHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"abc123", "def456", "ghi789"}
+	if len(got) != len(want) {
+		t.Fatalf("acceptableHashes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("acceptableHashes()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAcceptableHashesFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "SHA256SUMS")
+	contents := "abc123  file1.txt\ndef456  file2.txt\n"
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := cond{Value: path}
+	got, err := c.acceptableHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"abc123", "def456"}
+	if len(got) != len(want) {
+		t.Fatalf("acceptableHashes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("acceptableHashes()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEvalCount(t *testing.T) {
+	cases := []struct {
+		expr string
+		got  int
+		want bool
+	}{
+		{"3", 3, true},
+		{"3", 4, false},
+		{"==3", 3, true},
+		{"==3", 2, false},
+		{">=3", 3, true},
+		{">=3", 2, false},
+		{"<=3", 3, true},
+		{"<=3", 4, false},
+		{"!=3", 4, true},
+		{"!=3", 3, false},
+		{">3", 4, true},
+		{">3", 3, false},
+		{"<3", 2, true},
+		{"<3", 3, false},
+		{" == 0 ", 0, true},
+	}
+	for _, c := range cases {
+		got, err := evalCount(c.expr, c.got)
+		if err != nil {
+			t.Fatalf("evalCount(%q, %d) returned error: %v", c.expr, c.got, err)
+		}
+		if got != c.want {
+			t.Errorf("evalCount(%q, %d) = %v, want %v", c.expr, c.got, got, c.want)
+		}
+	}
+}
+
+func TestEvalCountInvalid(t *testing.T) {
+	if _, err := evalCount("banana", 1); err == nil {
+		t.Error("evalCount() with a non-numeric expression should return an error")
+	}
+}
+
+func TestCompiledRegexCachesByPattern(t *testing.T) {
+	re1, err := compiledRegex(`^needle\d+$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	re2, err := compiledRegex(`^needle\d+$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re1 != re2 {
+		t.Error("compiledRegex() should return the same *Regexp for a repeated pattern")
+	}
+
+	re3, err := compiledRegex(`^other$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re1 == re3 {
+		t.Error("compiledRegex() should not share *Regexp instances across distinct patterns")
+	}
+}
+
+func TestCompiledRegexInvalidPattern(t *testing.T) {
+	if _, err := compiledRegex(`(`); err == nil {
+		t.Error("compiledRegex() with an invalid pattern should return an error")
+	}
+}
+
+func TestFileContainsCount(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	if err := os.WriteFile(path, []byte("foo bar foo baz foo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		count string
+		want  bool
+	}{
+		{"exact match", "==3", true},
+		{"bare number is shorthand for ==", "3", true},
+		{"exact mismatch", "==2", false},
+		{"at least", ">=2", true},
+		{"at most zero", "<=0", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cnd := cond{Path: path, Value: "foo", Count: c.count}
+			got, err := cnd.FileContains()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("FileContains() with Count %q = %v, want %v", c.count, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCommandOutputBasic(t *testing.T) {
+	// "echo hello" works verbatim under both sh and cmd.exe.
+	c := cond{Cmd: "echo hello", Value: "hello"}
+	got, err := c.CommandOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("CommandOutput() should match the echoed value")
+	}
+}
+
+func TestCommandContainsTimeoutKillsGrandchild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep isn't a Windows builtin")
+	}
+
+	c := cond{Cmd: "sleep 5", Value: "anything", Timeout: "200ms"}
+	start := time.Now()
+	_, err := c.CommandContains()
+	elapsed := time.Since(start)
+	if err == nil {
+		t.Fatal("CommandContains() should report a timeout error")
+	}
+	// Before shellCommandOutput killed the whole process group, a forked
+	// grandchild (dash forks rather than execs for plain commands) kept
+	// the output pipe open well past Timeout, and this returned after
+	// sleep's real ~5s duration instead.
+	if elapsed > 2*time.Second {
+		t.Fatalf("CommandContains() took %s, want close to its 200ms Timeout", elapsed)
+	}
+}
+
+func TestCommandOutputDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("pwd isn't a Windows builtin")
+	}
+
+	dir := t.TempDir()
+	c := cond{Cmd: "pwd", Value: dir, Dir: dir}
+	got, err := c.CommandOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("CommandOutput() should run the command in Dir")
+	}
+}
+
+func TestCommandOutputEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX-style variable expansion isn't available under cmd")
+	}
+
+	c := cond{Cmd: "echo $FOO", Value: "bar", Env: []string{"FOO=bar"}}
+	got, err := c.CommandOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("CommandOutput() should see Env variables set on the command")
+	}
+}
+
+func TestStripCheckSuffixes(t *testing.T) {
+	cases := []struct {
+		in          string
+		wantName    string
+		wantNegated bool
+		wantRegex   bool
+	}{
+		{"FileContains", "FileContains", false, false},
+		{"FileContainsNot", "FileContains", true, false},
+		{"FileContainsRegex", "FileContains", false, true},
+		{"FileContainsNotRegex", "FileContains", true, true},
+	}
+	for _, c := range cases {
+		name, negated, isRegex := stripCheckSuffixes(c.in)
+		if name != c.wantName || negated != c.wantNegated || isRegex != c.wantRegex {
+			t.Errorf("stripCheckSuffixes(%q) = (%q, %v, %v), want (%q, %v, %v)",
+				c.in, name, negated, isRegex, c.wantName, c.wantNegated, c.wantRegex)
+		}
+	}
+}
+
+func TestRunRegisteredCheck(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err, ok := runRegisteredCheck(cond{Path: dir}, "PathExists")
+	if !ok {
+		t.Fatal("runRegisteredCheck() should find the registered PathExists check")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result {
+		t.Error("runRegisteredCheck(PathExists) on an existing dir should return true")
+	}
+
+	if _, _, ok := runRegisteredCheck(cond{}, "NoSuchCheckType"); ok {
+		t.Error("runRegisteredCheck() should report ok == false for an unregistered check type")
+	}
+}
+
+func TestRunReflectedCheck(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err, ok := runReflectedCheck(cond{Path: dir}, "PathExists")
+	if !ok {
+		t.Fatal("runReflectedCheck() should dispatch to an existing cond method")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result {
+		t.Error("runReflectedCheck(PathExists) on an existing dir should return true")
+	}
+
+	if _, _, ok := runReflectedCheck(cond{}, "NoSuchMethod"); ok {
+		t.Error("runReflectedCheck() should recover from the panic and report ok == false for a nonexistent method")
+	}
+}
+
+// zzTestFakeCheck is a stand-in CheckType used only to verify RegisterCheck
+// wires a type into the registry without relying on (or colliding with)
+// any of the real built-ins registered in init().
+type zzTestFakeCheck struct{}
+
+func (zzTestFakeCheck) Name() string           { return "zzTestFakeCheck" }
+func (zzTestFakeCheck) RequiredArgs() []string { return nil }
+func (zzTestFakeCheck) Run(cond) (bool, error) { return true, nil }
+
+func TestRegisterCheck(t *testing.T) {
+	RegisterCheck(zzTestFakeCheck{})
+
+	result, err, ok := runRegisteredCheck(cond{}, "zzTestFakeCheck")
+	if !ok {
+		t.Fatal("RegisterCheck() should make the check type runnable via runRegisteredCheck")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result {
+		t.Error("runRegisteredCheck() should return the registered check's result")
+	}
+}
@@ -0,0 +1,17 @@
+// config.go holds the scoring image's top-level configuration knobs.
+
+package main
+
+import "time"
+
+// config is the scoring image's top-level configuration.
+type config struct {
+	// CommandTimeout is the default timeout applied to CommandContains and
+	// CommandOutput when a condition doesn't set its own Timeout.
+	CommandTimeout time.Duration
+}
+
+// conf is the loaded scoring image configuration.
+var conf = config{
+	CommandTimeout: 30 * time.Second,
+}
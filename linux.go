@@ -0,0 +1,51 @@
+//go:build linux
+
+package main
+
+import (
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// prepareCommandGroup puts cmd in its own process group, so
+// killCommandGroup can terminate it and any children it spawns (e.g. the
+// grandchildren a shell forks for ordinary commands) instead of just cmd
+// itself.
+func prepareCommandGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// killCommandGroup kills every process in cmd's process group. cmd must
+// have been started with prepareCommandGroup.
+func killCommandGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// setCommandUser configures cmd to run as the named system user, so
+// CommandContains/CommandOutput can scope a command's privileges instead
+// of always running as whatever account is scoring.
+func setCommandUser(cmd *exec.Cmd, username string) error {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return err
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return err
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}
+	return nil
+}
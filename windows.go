@@ -0,0 +1,34 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setCommandUser is not implemented on Windows: running a command as
+// another user requires a logon token (CreateProcessWithLogonW), which
+// os/exec doesn't expose.
+func setCommandUser(cmd *exec.Cmd, username string) error {
+	return fmt.Errorf("running a command as another user is not supported on windows")
+}
+
+// prepareCommandGroup puts cmd in its own process group via
+// CREATE_NEW_PROCESS_GROUP, so killCommandGroup can terminate it and any
+// children it spawns instead of just cmd itself.
+func prepareCommandGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// killCommandGroup kills cmd's process tree with taskkill: Windows has no
+// direct equivalent of sending a signal to a process group. cmd must have
+// been started with prepareCommandGroup.
+func killCommandGroup(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}